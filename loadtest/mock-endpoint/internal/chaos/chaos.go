@@ -0,0 +1,146 @@
+// Package chaos implements prikke's fault-injection profiles: timeouts,
+// resets, slow writes, truncated bodies and weighted status distributions.
+package chaos
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+)
+
+// Fault identifies which terminal fault was rolled for a request. The zero
+// value means no fault was injected.
+type Fault string
+
+const (
+	None         Fault = ""
+	Timeout      Fault = "timeout"
+	Reset        Fault = "reset"
+	Slow         Fault = "slow"
+	BodyTruncate Fault = "body_truncate"
+	StatusWeight Fault = "status_weighted"
+)
+
+// order is the documented, fixed check order: each fault's percentage is
+// rolled independently, and the first one that hits wins. status_weighted is
+// checked last and, unlike the others, always applies once reached (its
+// "percentage" is really a distribution over statuses, not a trigger chance).
+var order = []Fault{Timeout, Reset, Slow, BodyTruncate, StatusWeight}
+
+// weightedStatus pairs a status code with its relative weight in a
+// status_weighted distribution.
+type weightedStatus struct {
+	status int
+	weight int
+}
+
+// Spec is a parsed ?faults=... value.
+type Spec struct {
+	pct      map[Fault]int
+	weighted []weightedStatus
+}
+
+// ParseSpec parses a spec like
+// "timeout:5,reset:2,slow:10,body_truncate:3,status_weighted:500=4;503=1".
+func ParseSpec(raw string) (Spec, error) {
+	spec := Spec{pct: map[Fault]int{}}
+	if raw == "" {
+		return spec, nil
+	}
+
+	for _, tok := range strings.Split(raw, ",") {
+		tok = strings.TrimSpace(tok)
+		if tok == "" {
+			continue
+		}
+		name, val, ok := strings.Cut(tok, ":")
+		if !ok {
+			return Spec{}, fmt.Errorf("chaos: malformed fault token %q", tok)
+		}
+
+		fault := Fault(name)
+		if fault == StatusWeight {
+			ws, err := parseWeights(val)
+			if err != nil {
+				return Spec{}, err
+			}
+			spec.weighted = ws
+			continue
+		}
+
+		pct, err := strconv.Atoi(val)
+		if err != nil {
+			return Spec{}, fmt.Errorf("chaos: invalid percentage for %q: %w", name, err)
+		}
+		spec.pct[fault] = pct
+	}
+
+	return spec, nil
+}
+
+func parseWeights(val string) ([]weightedStatus, error) {
+	var ws []weightedStatus
+	for _, pair := range strings.Split(val, ";") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		statusStr, weightStr, ok := strings.Cut(pair, "=")
+		if !ok {
+			return nil, fmt.Errorf("chaos: malformed status_weighted pair %q", pair)
+		}
+		status, err := strconv.Atoi(statusStr)
+		if err != nil {
+			return nil, fmt.Errorf("chaos: invalid status in %q: %w", pair, err)
+		}
+		weight, err := strconv.Atoi(weightStr)
+		if err != nil {
+			return nil, fmt.Errorf("chaos: invalid weight in %q: %w", pair, err)
+		}
+		ws = append(ws, weightedStatus{status: status, weight: weight})
+	}
+	return ws, nil
+}
+
+// Empty reports whether the spec declares no faults at all.
+func (s Spec) Empty() bool {
+	return len(s.pct) == 0 && len(s.weighted) == 0
+}
+
+// Roll checks each fault in the documented order and returns the first one
+// that fires, or None if none did.
+func (s Spec) Roll() Fault {
+	for _, f := range order {
+		if f == StatusWeight {
+			if len(s.weighted) > 0 {
+				return StatusWeight
+			}
+			continue
+		}
+		if pct := s.pct[f]; pct > 0 && rand.Intn(100) < pct {
+			return f
+		}
+	}
+	return None
+}
+
+// PickStatus picks a status code from the status_weighted distribution,
+// proportionally to each status's weight.
+func (s Spec) PickStatus() int {
+	total := 0
+	for _, w := range s.weighted {
+		total += w.weight
+	}
+	if total <= 0 {
+		return 0
+	}
+	r := rand.Intn(total)
+	for _, w := range s.weighted {
+		if r < w.weight {
+			return w.status
+		}
+		r -= w.weight
+	}
+	return s.weighted[len(s.weighted)-1].status
+}
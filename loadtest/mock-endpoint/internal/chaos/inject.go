@@ -0,0 +1,107 @@
+package chaos
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+)
+
+// Apply performs the connection-level side effects for a terminal fault
+// other than StatusWeight, which the caller handles by picking a status via
+// PickStatus and continuing the normal response path. It reports whether the
+// response was fully handled (the caller must not write to w afterwards).
+func Apply(w http.ResponseWriter, f Fault, status int, body string, delayMs int) (bool, error) {
+	switch f {
+	case Timeout:
+		return applyTimeout(w)
+	case Reset:
+		return applyReset(w)
+	case Slow:
+		return applySlow(w, status, body, delayMs)
+	case BodyTruncate:
+		return applyBodyTruncate(w, status, body)
+	default:
+		return false, nil
+	}
+}
+
+// applyTimeout hijacks the connection and never writes to it, simulating a
+// server that hangs forever.
+func applyTimeout(w http.ResponseWriter) (bool, error) {
+	conn, _, err := hijack(w)
+	if err != nil {
+		return false, err
+	}
+	_ = conn // intentionally never written to or closed
+	return true, nil
+}
+
+// applyReset hijacks the connection and forces a TCP RST instead of a clean
+// FIN, simulating a backend that crashes mid-request.
+func applyReset(w http.ResponseWriter) (bool, error) {
+	conn, _, err := hijack(w)
+	if err != nil {
+		return false, err
+	}
+	if tcp, ok := conn.(*net.TCPConn); ok {
+		_ = tcp.SetLinger(0)
+	}
+	return true, conn.Close()
+}
+
+// applySlow writes the status and body one byte at a time, pacing each byte
+// by delayMs (defaulting to 50ms), simulating a slow upstream.
+func applySlow(w http.ResponseWriter, status int, body string, delayMs int) (bool, error) {
+	interval := time.Duration(delayMs) * time.Millisecond
+	if interval <= 0 {
+		interval = 50 * time.Millisecond
+	}
+
+	flusher, ok := w.(http.Flusher)
+	w.WriteHeader(status)
+	for i := 0; i < len(body); i++ {
+		if _, err := w.Write([]byte{body[i]}); err != nil {
+			return true, err
+		}
+		if ok {
+			flusher.Flush()
+		}
+		time.Sleep(interval)
+	}
+	return true, nil
+}
+
+// applyBodyTruncate advertises the full Content-Length but closes the
+// connection after writing only half the body, simulating a backend that
+// dies mid-response.
+func applyBodyTruncate(w http.ResponseWriter, status int, body string) (bool, error) {
+	w.Header().Set("Content-Length", fmt.Sprintf("%d", len(body)))
+	w.WriteHeader(status)
+
+	cut := len(body) / 2
+	if _, err := w.Write([]byte(body[:cut])); err != nil {
+		return true, err
+	}
+	// Hijack only flushes the chunkWriter, not the bufio.Writer that Write
+	// just buffered into, so flush explicitly or the partial write never
+	// reaches the wire for small bodies.
+	if flusher, ok := w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+
+	conn, _, err := hijack(w)
+	if err != nil {
+		return true, err
+	}
+	return true, conn.Close()
+}
+
+func hijack(w http.ResponseWriter) (net.Conn, *bufio.ReadWriter, error) {
+	hj, ok := w.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("chaos: response writer does not support hijacking")
+	}
+	return hj.Hijack()
+}
@@ -0,0 +1,34 @@
+// Package metrics exposes prikke's own traffic as Prometheus metrics so
+// users can graph the mock's behavior during load tests without a sidecar.
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	requestsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "prikke_requests_total",
+		Help: "Total requests handled by the mock endpoint.",
+	}, []string{"method", "route", "status", "fault"})
+
+	requestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "prikke_request_duration_seconds",
+		Help:    "Latency of requests handled by the mock endpoint, including injected delay/jitter.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method", "route"})
+)
+
+// CatchallRoute is the route label used when a request matched no configured
+// scenario.
+const CatchallRoute = "catchall"
+
+// Observe records one handled request. fault should be "none" when no fault
+// was injected, or the fault's name otherwise.
+func Observe(method, route, status, fault string, duration time.Duration) {
+	requestsTotal.WithLabelValues(method, route, status, fault).Inc()
+	requestDuration.WithLabelValues(method, route).Observe(duration.Seconds())
+}
@@ -0,0 +1,33 @@
+package recorder
+
+import "net/http"
+
+// Matcher selects the best-matching recorded entry for an inbound request,
+// so replay can plug in stricter or looser matching strategies.
+type Matcher interface {
+	Match(r *http.Request, body []byte, entries []Entry) (Entry, bool)
+}
+
+// DefaultMatcher matches by method and path, optionally also requiring the
+// query string and/or body to match exactly.
+type DefaultMatcher struct {
+	MatchQuery bool
+	MatchBody  bool
+}
+
+// Match returns the first recorded entry matching r per the matcher's rules.
+func (m DefaultMatcher) Match(r *http.Request, body []byte, entries []Entry) (Entry, bool) {
+	for _, e := range entries {
+		if e.Method != r.Method || e.Path != r.URL.Path {
+			continue
+		}
+		if m.MatchQuery && e.Query != r.URL.RawQuery {
+			continue
+		}
+		if m.MatchBody && e.Body != string(body) {
+			continue
+		}
+		return e, true
+	}
+	return Entry{}, false
+}
@@ -0,0 +1,108 @@
+package recorder
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Passthrough proxies requests to a real upstream backend, optionally
+// recording each exchange so it can be replayed later.
+type Passthrough struct {
+	upstream *url.URL
+	client   *http.Client
+	rec      *Recorder
+}
+
+// NewPassthrough builds a Passthrough proxying to upstream. rec may be nil to
+// proxy without recording.
+func NewPassthrough(upstream string, rec *Recorder) (*Passthrough, error) {
+	u, err := url.Parse(upstream)
+	if err != nil {
+		return nil, fmt.Errorf("recorder: parse upstream %s: %w", upstream, err)
+	}
+	return &Passthrough{upstream: u, client: &http.Client{}, rec: rec}, nil
+}
+
+// ServeHTTP forwards the request to the upstream and relays its response,
+// recording the exchange if a Recorder was configured.
+func (p *Passthrough) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var reqBody []byte
+	if r.Body != nil {
+		reqBody, _ = io.ReadAll(r.Body)
+		r.Body.Close()
+	}
+
+	target := *p.upstream
+	// Join rather than overwrite: -passthrough http://host/api must keep its
+	// /api prefix instead of having the incoming request path replace it.
+	target.Path = singleJoiningSlash(p.upstream.Path, r.URL.Path)
+	target.RawQuery = r.URL.RawQuery
+
+	proxyReq, err := http.NewRequestWithContext(r.Context(), r.Method, target.String(), bytes.NewReader(reqBody))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("recorder: build proxy request: %v", err), http.StatusBadGateway)
+		return
+	}
+	proxyReq.Header = r.Header.Clone()
+
+	resp, err := p.client.Do(proxyReq)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("recorder: upstream request failed: %v", err), http.StatusBadGateway)
+		return
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("recorder: read upstream response: %v", err), http.StatusBadGateway)
+		return
+	}
+
+	for k, vs := range resp.Header {
+		for _, v := range vs {
+			w.Header().Add(k, v)
+		}
+	}
+	w.WriteHeader(resp.StatusCode)
+	w.Write(respBody)
+
+	if p.rec == nil {
+		return
+	}
+	entry := Entry{
+		Method:      r.Method,
+		Path:        r.URL.Path,
+		Query:       r.URL.RawQuery,
+		Headers:     r.Header,
+		Body:        string(reqBody),
+		Timestamp:   time.Now(),
+		Status:      resp.StatusCode,
+		RespHeaders: resp.Header,
+		RespBody:    string(respBody),
+	}
+	if err := p.rec.Write(entry); err != nil {
+		log.Printf("recorder: write entry: %v", err)
+	}
+}
+
+// singleJoiningSlash joins an upstream base path with an incoming request
+// path with exactly one slash between them: a doubled slash is collapsed and
+// a missing one is inserted.
+func singleJoiningSlash(base, suffix string) string {
+	baseSlash := strings.HasSuffix(base, "/")
+	suffixSlash := strings.HasPrefix(suffix, "/")
+	switch {
+	case baseSlash && suffixSlash:
+		return base + suffix[1:]
+	case !baseSlash && !suffixSlash:
+		return base + "/" + suffix
+	default:
+		return base + suffix
+	}
+}
@@ -0,0 +1,115 @@
+package recorder
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+// Recorder appends Entry values as JSONL to a recording file.
+type Recorder struct {
+	mu  sync.Mutex
+	f   *os.File
+	enc *json.Encoder
+}
+
+// NewRecorder opens (creating or appending to) a JSONL recording file.
+func NewRecorder(path string) (*Recorder, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("recorder: open %s: %w", path, err)
+	}
+	return &Recorder{f: f, enc: json.NewEncoder(f)}, nil
+}
+
+// Write appends a single entry.
+func (rec *Recorder) Write(e Entry) error {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	return rec.enc.Encode(e)
+}
+
+// Close closes the underlying recording file.
+func (rec *Recorder) Close() error {
+	return rec.f.Close()
+}
+
+// Wrap returns a handler that passes every response written by next through
+// to the client as it is written, and records the request/response exchange
+// once next returns.
+func Wrap(rec *Recorder, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var reqBody []byte
+		if r.Body != nil {
+			reqBody, _ = io.ReadAll(r.Body)
+			r.Body.Close()
+			r.Body = io.NopCloser(bytes.NewReader(reqBody))
+		}
+
+		tee := newTeeWriter(w)
+		next.ServeHTTP(tee, r)
+
+		entry := Entry{
+			Method:      r.Method,
+			Path:        r.URL.Path,
+			Query:       r.URL.RawQuery,
+			Headers:     r.Header,
+			Body:        string(reqBody),
+			Timestamp:   time.Now(),
+			Status:      tee.status,
+			RespHeaders: w.Header(),
+			RespBody:    tee.buf.String(),
+		}
+		if err := rec.Write(entry); err != nil {
+			log.Printf("recorder: write entry: %v", err)
+		}
+	})
+}
+
+// teeWriter captures the status and body written through it while still
+// forwarding everything to the real ResponseWriter.
+type teeWriter struct {
+	http.ResponseWriter
+	status int
+	buf    bytes.Buffer
+}
+
+func newTeeWriter(w http.ResponseWriter) *teeWriter {
+	return &teeWriter{ResponseWriter: w, status: http.StatusOK}
+}
+
+func (t *teeWriter) WriteHeader(status int) {
+	t.status = status
+	t.ResponseWriter.WriteHeader(status)
+}
+
+func (t *teeWriter) Write(b []byte) (int, error) {
+	t.buf.Write(b)
+	return t.ResponseWriter.Write(b)
+}
+
+// Flush forwards to the underlying writer when it supports streaming, so
+// wrapping with the recorder doesn't break SSE/chunked responses.
+func (t *teeWriter) Flush() {
+	if f, ok := t.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Hijack forwards to the underlying writer so recording can be layered on
+// top of chaos faults that need a raw connection.
+func (t *teeWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hj, ok := t.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("recorder: underlying response writer does not support hijacking")
+	}
+	return hj.Hijack()
+}
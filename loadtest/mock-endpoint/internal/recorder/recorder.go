@@ -0,0 +1,23 @@
+// Package recorder implements prikke's request recording and replay mode:
+// capturing real traffic as JSONL and later replaying the matching response
+// for each inbound request.
+package recorder
+
+import (
+	"net/http"
+	"time"
+)
+
+// Entry is one recorded request/response exchange, as written to a JSONL
+// recording file.
+type Entry struct {
+	Method      string      `json:"method"`
+	Path        string      `json:"path"`
+	Query       string      `json:"query"`
+	Headers     http.Header `json:"headers"`
+	Body        string      `json:"body"`
+	Timestamp   time.Time   `json:"timestamp"`
+	Status      int         `json:"status"`
+	RespHeaders http.Header `json:"response_headers"`
+	RespBody    string      `json:"response_body"`
+}
@@ -0,0 +1,65 @@
+package recorder
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+)
+
+// Replayer serves responses from a previously recorded JSONL file.
+type Replayer struct {
+	entries []Entry
+	matcher Matcher
+}
+
+// LoadReplayer reads every entry from a recording file written by Recorder.
+func LoadReplayer(path string, matcher Matcher) (*Replayer, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("recorder: open %s: %w", path, err)
+	}
+	defer f.Close()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	for scanner.Scan() {
+		var e Entry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			return nil, fmt.Errorf("recorder: parse %s: %w", path, err)
+		}
+		entries = append(entries, e)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("recorder: read %s: %w", path, err)
+	}
+
+	return &Replayer{entries: entries, matcher: matcher}, nil
+}
+
+// ServeHTTP replays the best-matching recorded response, or 404s when
+// nothing in the recording matches the request.
+func (rp *Replayer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var body []byte
+	if r.Body != nil {
+		body, _ = io.ReadAll(r.Body)
+		r.Body.Close()
+	}
+
+	e, ok := rp.matcher.Match(r, body, rp.entries)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	for k, vs := range e.RespHeaders {
+		for _, v := range vs {
+			w.Header().Add(k, v)
+		}
+	}
+	w.WriteHeader(e.Status)
+	fmt.Fprint(w, e.RespBody)
+}
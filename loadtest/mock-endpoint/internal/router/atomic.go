@@ -0,0 +1,29 @@
+package router
+
+import "sync"
+
+// Atomic holds a *Router behind a RWMutex so it can be hot-swapped (e.g. on
+// SIGHUP config reload) while requests are being served concurrently.
+type Atomic struct {
+	mu sync.RWMutex
+	rt *Router
+}
+
+// NewAtomic wraps an initial Router, which may be nil.
+func NewAtomic(rt *Router) *Atomic {
+	return &Atomic{rt: rt}
+}
+
+// Load returns the current Router, or nil if none has been set.
+func (a *Atomic) Load() *Router {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.rt
+}
+
+// Store atomically swaps in a new Router.
+func (a *Atomic) Store(rt *Router) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.rt = rt
+}
@@ -0,0 +1,31 @@
+// Package router matches incoming requests against configured scenarios.
+package router
+
+import "github.com/gautema/prikke/loadtest/mock-endpoint/internal/scenario"
+
+// Router holds the scenarios loaded from a config file and matches requests
+// against them in declaration order.
+type Router struct {
+	routes []scenario.Scenario
+}
+
+// New builds a Router from the routes of a parsed config.
+func New(routes []scenario.Scenario) *Router {
+	return &Router{routes: routes}
+}
+
+// Match returns the first scenario whose method and path match the request,
+// and whether a match was found. An empty Method on a scenario matches any
+// method.
+func (rt *Router) Match(method, path string) (scenario.Scenario, bool) {
+	for _, s := range rt.routes {
+		if s.Path != path {
+			continue
+		}
+		if s.Method != "" && s.Method != method {
+			continue
+		}
+		return s, true
+	}
+	return scenario.Scenario{}, false
+}
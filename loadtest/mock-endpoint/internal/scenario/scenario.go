@@ -0,0 +1,42 @@
+// Package scenario defines the config-file schema for prikke's route-specific
+// mock behaviors and loads it from disk.
+package scenario
+
+// Scenario describes how a single route should be mocked: which requests it
+// matches and how it should respond.
+type Scenario struct {
+	// Method is the HTTP method to match, e.g. "GET". Empty matches any method.
+	Method string `yaml:"method" json:"method"`
+	// Path is the request path to match, e.g. "/users/1".
+	Path string `yaml:"path" json:"path"`
+
+	// Status is the response status code. Defaults to 200.
+	Status int `yaml:"status" json:"status"`
+	// Body is an inline response body. Mutually exclusive with BodyFile.
+	Body string `yaml:"body" json:"body"`
+	// BodyFile is a path to a file whose contents are used as the response
+	// body. Mutually exclusive with Body.
+	BodyFile string `yaml:"body_file" json:"body_file"`
+	// Template marks Body/BodyFile as a Go text/template that is executed
+	// against the incoming request before being written out.
+	Template bool `yaml:"template" json:"template"`
+	// Headers are extra response headers to set.
+	Headers map[string]string `yaml:"headers" json:"headers"`
+
+	// DelayMs is a fixed response delay in milliseconds.
+	DelayMs int `yaml:"delay" json:"delay"`
+	// JitterMs adds a random delay of 0-JitterMs milliseconds on top of DelayMs.
+	JitterMs int `yaml:"jitter" json:"jitter"`
+	// FailRate is the percentage (0-100) of requests that should get a 500
+	// instead of Status.
+	FailRate int `yaml:"fail_rate" json:"fail_rate"`
+	// Faults is a chaos spec string, e.g.
+	// "timeout:5,reset:2,slow:10,body_truncate:3,status_weighted:500=4;503=1".
+	// See the chaos package for the format and evaluation order.
+	Faults string `yaml:"faults" json:"faults"`
+}
+
+// Config is the top-level shape of a prikke scenario file.
+type Config struct {
+	Routes []Scenario `yaml:"routes" json:"routes"`
+}
@@ -0,0 +1,72 @@
+// Package stream implements prikke's streaming response modes: Server-Sent
+// Events and plain chunked JSON, both cancellable mid-stream.
+package stream
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// SSE writes n "data: {...}\n\n" frames paced by interval, flushing after
+// each one. It stops early if the request is cancelled.
+func SSE(w http.ResponseWriter, r *http.Request, events int, interval time.Duration) error {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		return fmt.Errorf("stream: response writer does not support flushing")
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	for i := 0; i < events; i++ {
+		if _, err := fmt.Fprintf(w, "data: {\"event\":%d}\n\n", i); err != nil {
+			return err
+		}
+		flusher.Flush()
+
+		if i == events-1 {
+			break
+		}
+		select {
+		case <-r.Context().Done():
+			return r.Context().Err()
+		case <-time.After(interval):
+		}
+	}
+	return nil
+}
+
+// Chunked writes n arbitrary JSON fragments paced by interval, flushing after
+// each one so they're delivered as separate HTTP chunks. It stops early if
+// the request is cancelled.
+func Chunked(w http.ResponseWriter, r *http.Request, chunks int, interval time.Duration) error {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		return fmt.Errorf("stream: response writer does not support flushing")
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	for i := 0; i < chunks; i++ {
+		if _, err := fmt.Fprintf(w, "{\"chunk\":%d}\n", i); err != nil {
+			return err
+		}
+		flusher.Flush()
+
+		if i == chunks-1 {
+			break
+		}
+		select {
+		case <-r.Context().Done():
+			return r.Context().Err()
+		case <-time.After(interval):
+		}
+	}
+	return nil
+}
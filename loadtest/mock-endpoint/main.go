@@ -1,51 +1,321 @@
 package main
 
 import (
+	"bytes"
+	"context"
+	"flag"
 	"fmt"
+	"log"
 	"math/rand"
 	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
 	"strconv"
+	"syscall"
+	"text/template"
 	"time"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/gautema/prikke/loadtest/mock-endpoint/internal/chaos"
+	"github.com/gautema/prikke/loadtest/mock-endpoint/internal/metrics"
+	"github.com/gautema/prikke/loadtest/mock-endpoint/internal/recorder"
+	"github.com/gautema/prikke/loadtest/mock-endpoint/internal/router"
+	"github.com/gautema/prikke/loadtest/mock-endpoint/internal/scenario"
+	"github.com/gautema/prikke/loadtest/mock-endpoint/internal/stream"
 )
 
 func main() {
-	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
-		// ?delay=100 — fixed delay in ms
-		if d := r.URL.Query().Get("delay"); d != "" {
-			if ms, err := strconv.Atoi(d); err == nil {
-				time.Sleep(time.Duration(ms) * time.Millisecond)
+	configPath := flag.String("config", "", "path to a scenario config file (.yaml, .yml or .json); reloaded on SIGHUP")
+	recordPath := flag.String("record", "", "record requests/responses as JSONL to this file")
+	replayPath := flag.String("replay", "", "replay recorded responses from this JSONL file instead of mocking live")
+	passthrough := flag.String("passthrough", "", "proxy to this upstream URL instead of mocking (combine with -record to capture a session)")
+	drainTimeout := flag.Duration("shutdown-timeout", 10*time.Second, "how long to wait for in-flight requests to finish on shutdown")
+	flag.Parse()
+
+	ar := router.NewAtomic(nil)
+	if *configPath != "" {
+		if err := reloadRouter(ar, *configPath); err != nil {
+			log.Fatalf("mock-endpoint: %v", err)
+		}
+	}
+
+	var handler http.Handler = newMockHandler(ar)
+	switch {
+	case *replayPath != "":
+		rp, err := recorder.LoadReplayer(*replayPath, recorder.DefaultMatcher{MatchQuery: true})
+		if err != nil {
+			log.Fatalf("mock-endpoint: %v", err)
+		}
+		handler = rp
+	case *passthrough != "":
+		var rec *recorder.Recorder
+		if *recordPath != "" {
+			var err error
+			rec, err = recorder.NewRecorder(*recordPath)
+			if err != nil {
+				log.Fatalf("mock-endpoint: %v", err)
 			}
+			defer rec.Close()
+		}
+		pt, err := recorder.NewPassthrough(*passthrough, rec)
+		if err != nil {
+			log.Fatalf("mock-endpoint: %v", err)
 		}
+		handler = pt
+	case *recordPath != "":
+		rec, err := recorder.NewRecorder(*recordPath)
+		if err != nil {
+			log.Fatalf("mock-endpoint: %v", err)
+		}
+		defer rec.Close()
+		handler = recorder.Wrap(rec, handler)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+	mux.Handle("/", handler)
+
+	srv := &http.Server{Addr: ":8080", Handler: mux}
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP)
+	idleConnsClosed := make(chan struct{})
+	go func() {
+		for sig := range sigCh {
+			if sig == syscall.SIGHUP {
+				if *configPath == "" {
+					log.Printf("mock-endpoint: received SIGHUP but no -config was given, ignoring")
+					continue
+				}
+				if err := reloadRouter(ar, *configPath); err != nil {
+					log.Printf("mock-endpoint: reload %s: %v", *configPath, err)
+				} else {
+					log.Printf("mock-endpoint: reloaded scenario config from %s", *configPath)
+				}
+				continue
+			}
 
-		// ?jitter=500 — random delay 0-500ms (simulates real endpoints)
-		if j := r.URL.Query().Get("jitter"); j != "" {
-			if ms, err := strconv.Atoi(j); err == nil {
-				time.Sleep(time.Duration(rand.Intn(ms)) * time.Millisecond)
+			log.Printf("mock-endpoint: received %s, draining (timeout %s)", sig, *drainTimeout)
+			ctx, cancel := context.WithTimeout(context.Background(), *drainTimeout)
+			if err := srv.Shutdown(ctx); err != nil {
+				log.Printf("mock-endpoint: shutdown: %v", err)
 			}
+			cancel()
+			close(idleConnsClosed)
+			return
 		}
+	}()
 
-		// ?status=503 — return specific status code
+	fmt.Println("Mock endpoint listening on :8080")
+	if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		log.Fatalf("mock-endpoint: %v", err)
+	}
+	<-idleConnsClosed
+}
+
+// reloadRouter parses the scenario config at path and atomically swaps it
+// into ar.
+func reloadRouter(ar *router.Atomic, path string) error {
+	cfg, err := scenario.Load(path)
+	if err != nil {
+		return err
+	}
+	ar.Store(router.New(cfg.Routes))
+	return nil
+}
+
+// newMockHandler builds the default query/config-driven mock handler. ar may
+// be hot-swapped concurrently (e.g. on SIGHUP config reload).
+func newMockHandler(ar *router.Atomic) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		route := metrics.CatchallRoute
 		status := 200
-		if s := r.URL.Query().Get("status"); s != "" {
-			if code, err := strconv.Atoi(s); err == nil {
-				status = code
+		fault := "none"
+		defer func() {
+			metrics.Observe(r.Method, route, strconv.Itoa(status), fault, time.Since(start))
+		}()
+
+		s := scenario.Scenario{Status: 200}
+		if rt := ar.Load(); rt != nil {
+			if matched, ok := rt.Match(r.Method, r.URL.Path); ok {
+				s = matched
+				if s.Status == 0 {
+					s.Status = 200
+				}
+				route = matched.Path
+			}
+		}
+		applyQueryOverrides(&s, r.URL.Query())
+
+		// delay — fixed response delay in ms
+		if s.DelayMs > 0 {
+			time.Sleep(time.Duration(s.DelayMs) * time.Millisecond)
+		}
+
+		// jitter — random delay 0-jitter ms on top of delay (simulates real endpoints)
+		if s.JitterMs > 0 {
+			time.Sleep(time.Duration(rand.Intn(s.JitterMs)) * time.Millisecond)
+		}
+
+		// ?stream=sse&events=10&interval=200 or ?stream=chunked&chunks=5&interval=100
+		if handled, err := serveStream(w, r); handled {
+			if err != nil {
+				log.Printf("mock-endpoint: stream: %v", err)
 			}
+			return
 		}
 
-		// ?fail_rate=10 — fail 10% of requests with 500
-		if f := r.URL.Query().Get("fail_rate"); f != "" {
-			if pct, err := strconv.Atoi(f); err == nil {
-				if rand.Intn(100) < pct {
-					status = 500
+		status = s.Status
+
+		// fail_rate — fail pct% of requests with 500
+		if s.FailRate > 0 && rand.Intn(100) < s.FailRate {
+			status = 500
+		}
+
+		for k, v := range s.Headers {
+			w.Header().Set(k, v)
+		}
+
+		body, err := renderBody(s, r)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("mock-endpoint: %v", err), http.StatusInternalServerError)
+			return
+		}
+		if body == "" {
+			body = fmt.Sprintf(`{"status":"ok","method":"%s","path":"%s"}`, r.Method, r.URL.Path)
+		}
+
+		if w.Header().Get("Content-Type") == "" {
+			w.Header().Set("Content-Type", "application/json")
+		}
+
+		// faults — richer fault injection beyond fail_rate; see the chaos
+		// package for the evaluation order and semantics of each fault.
+		spec, err := chaos.ParseSpec(s.Faults)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("mock-endpoint: %v", err), http.StatusInternalServerError)
+			return
+		}
+		if !spec.Empty() {
+			switch f := spec.Roll(); f {
+			case chaos.None:
+				// fall through to the normal response below
+			case chaos.StatusWeight:
+				status = spec.PickStatus()
+				fault = string(f)
+			default:
+				fault = string(f)
+				if handled, err := chaos.Apply(w, f, status, body, s.DelayMs); handled {
+					if f == chaos.Timeout || f == chaos.Reset {
+						// The connection was hijacked before anything was
+						// written; reporting the scenario's status would
+						// claim a response that was never actually sent.
+						status = 0
+					}
+					if err != nil {
+						log.Printf("mock-endpoint: fault %s: %v", f, err)
+					}
+					return
 				}
 			}
 		}
 
-		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(status)
-		fmt.Fprintf(w, `{"status":"ok","method":"%s","path":"%s"}`, r.Method, r.URL.Path)
-	})
+		fmt.Fprint(w, body)
+	}
+}
 
-	fmt.Println("Mock endpoint listening on :8080")
-	http.ListenAndServe(":8080", nil)
+// applyQueryOverrides applies the legacy ?delay, ?jitter, ?status and
+// ?fail_rate query parameters on top of a matched (or default) scenario.
+// Query parameters always take precedence so ad-hoc testing stays cheap.
+func applyQueryOverrides(s *scenario.Scenario, q url.Values) {
+	if d := q.Get("delay"); d != "" {
+		if ms, err := strconv.Atoi(d); err == nil {
+			s.DelayMs = ms
+		}
+	}
+	if j := q.Get("jitter"); j != "" {
+		if ms, err := strconv.Atoi(j); err == nil {
+			s.JitterMs = ms
+		}
+	}
+	if st := q.Get("status"); st != "" {
+		if code, err := strconv.Atoi(st); err == nil {
+			s.Status = code
+		}
+	}
+	if f := q.Get("fail_rate"); f != "" {
+		if pct, err := strconv.Atoi(f); err == nil {
+			s.FailRate = pct
+		}
+	}
+	if f := q.Get("faults"); f != "" {
+		s.Faults = f
+	}
+}
+
+// serveStream handles the ?stream=sse and ?stream=chunked query params. It
+// reports whether the request was handled as a stream.
+func serveStream(w http.ResponseWriter, r *http.Request) (bool, error) {
+	q := r.URL.Query()
+	mode := q.Get("stream")
+	if mode == "" {
+		return false, nil
+	}
+
+	interval := 100 * time.Millisecond
+	if ms, err := strconv.Atoi(q.Get("interval")); err == nil {
+		interval = time.Duration(ms) * time.Millisecond
+	}
+
+	switch mode {
+	case "sse":
+		events := 10
+		if n, err := strconv.Atoi(q.Get("events")); err == nil {
+			events = n
+		}
+		return true, stream.SSE(w, r, events, interval)
+	case "chunked":
+		chunks := 5
+		if n, err := strconv.Atoi(q.Get("chunks")); err == nil {
+			chunks = n
+		}
+		return true, stream.Chunked(w, r, chunks, interval)
+	default:
+		return true, fmt.Errorf("unknown stream mode %q", mode)
+	}
+}
+
+// renderBody resolves a scenario's response body, reading BodyFile from disk
+// and executing it as a text/template when Template is set.
+func renderBody(s scenario.Scenario, r *http.Request) (string, error) {
+	raw := s.Body
+	if s.BodyFile != "" {
+		data, err := os.ReadFile(s.BodyFile)
+		if err != nil {
+			return "", fmt.Errorf("read body_file %s: %w", s.BodyFile, err)
+		}
+		raw = string(data)
+	}
+	if raw == "" || !s.Template {
+		return raw, nil
+	}
+
+	tmpl, err := template.New("body").Parse(raw)
+	if err != nil {
+		return "", fmt.Errorf("parse body template: %w", err)
+	}
+	var buf bytes.Buffer
+	data := struct {
+		Method string
+		Path   string
+		Query  url.Values
+	}{r.Method, r.URL.Path, r.URL.Query()}
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("execute body template: %w", err)
+	}
+	return buf.String(), nil
 }